@@ -0,0 +1,172 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package admin
+
+/*
+
+This file contains the admin JSON-RPC surface of the renderhive daemon
+(analogous to Ethereum's admin_addPeer/admin_removePeer namespace). It lets an
+operator manage HCS topic subscriptions and job topics, inspect node status,
+submit/list/cancel render jobs, and check/transfer the operator account's
+HBAR balance, all without restarting the daemon.
+
+The server only ever binds to a Unix domain socket created with 0600
+permissions, so access is gated by filesystem permissions rather than a
+bearer token; this mirrors how Docker and geth's IPC endpoint are secured.
+
+*/
+
+import (
+
+  // standard
+  "net"
+  "net/rpc"
+  "net/rpc/jsonrpc"
+  "os"
+  "time"
+
+  // internal
+  "renderhive/logger"
+)
+
+// Manager is the subset of ServiceApp the admin service needs in order to
+// manage HCS topic subscriptions and job topics, report node status, accept
+// render jobs, and inspect/move the operator account's HBAR balance, all at
+// runtime
+type Manager interface {
+
+  // topics
+  AddTopic(topicID string, startTime time.Time) error
+  RemoveTopic(topicID string) error
+  ListTopics() []string
+
+  AddJobTopic(topicID string) error
+  RemoveJobTopic(topicID string) error
+
+  // ResetTopicCheckpoint clears the persisted checkpoint for a topic, so its
+  // next subscription replays from the beginning (e.g. for reindexing)
+  ResetTopicCheckpoint(topicID string) error
+
+  // SendTopicMessage submits a message to an HCS topic and returns the
+  // Hedera transaction ID
+  SendTopicMessage(topicID string, payload []byte) (string, error)
+
+  // node
+  NodeStatus() NodeStatus
+
+  // jobs
+  SubmitJob(jobFilePath string) (string, error)
+  ListJobs() []string
+  CancelJob(jobID string) error
+
+  // account
+  AccountBalance() (string, error)
+  AccountTransfer(toAccountID string, amount string) (string, error)
+
+}
+
+// RPCName is the net/rpc registration name Service is exposed under, so every
+// method is dispatched as RPCName + "." + MethodName, e.g. "Admin.AddTopic".
+// Callers (renderhive/cli) must build their Call strings from this constant
+// instead of a hardcoded literal, so the two can never drift apart again.
+const RPCName = "Admin"
+
+// Server hosts the admin JSON-RPC API on a Unix domain socket
+type Server struct {
+
+  SocketPath string
+
+  listener net.Listener
+  rpcServer *rpc.Server
+
+}
+
+// NewServer creates an admin server that exposes the Admin.* RPC methods for
+// the given Manager
+func NewServer(socketPath string, manager Manager) (*Server, error) {
+
+  rpcServer := rpc.NewServer()
+  if err := rpcServer.RegisterName(RPCName, &Service{manager: manager}); err != nil {
+    return nil, err
+  }
+
+  return &Server{
+    SocketPath: socketPath,
+    rpcServer: rpcServer,
+  }, nil
+
+}
+
+// Start binds the Unix domain socket and begins serving admin RPC requests in
+// the background
+func (server *Server) Start() error {
+
+  // remove a stale socket file from a previous, unclean shutdown
+  os.Remove(server.SocketPath)
+
+  listener, err := net.Listen("unix", server.SocketPath)
+  if err != nil {
+    return err
+  }
+
+  // restrict the socket to the owner, since it grants full admin access
+  if err := os.Chmod(server.SocketPath, 0600); err != nil {
+    listener.Close()
+    return err
+  }
+
+  server.listener = listener
+
+  go func() {
+
+    for {
+
+      conn, err := listener.Accept()
+      if err != nil {
+        // listener.Close() was called during shutdown
+        return
+      }
+
+      go server.rpcServer.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+    }
+
+  }()
+
+  logger.RenderhiveLogger.Main.Info().Msg("Admin RPC server listening on " + server.SocketPath)
+
+  return nil
+
+}
+
+// Stop closes the admin socket
+func (server *Server) Stop() error {
+
+  if server.listener == nil {
+    return nil
+  }
+
+  err := server.listener.Close()
+  os.Remove(server.SocketPath)
+
+  return err
+
+}
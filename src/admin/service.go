@@ -0,0 +1,241 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package admin
+
+/*
+
+This file contains the net/rpc receiver that is registered under the "Admin"
+name, i.e. its methods are dispatched as Admin.AddTopic, Admin.RemoveTopic,
+and so on.
+
+*/
+
+import (
+
+  // standard
+  "time"
+
+)
+
+// AddTopicArgs are the arguments of Admin.AddTopic and Admin.AddJobTopic
+type AddTopicArgs struct {
+
+  // the HCS topic ID, e.g. "0.0.1234567"
+  TopicID string
+
+  // the consensus timestamp to start reading from if no checkpoint is stored
+  // for the topic; the zero value resumes from the beginning of the topic.
+  // Admin.AddJobTopic ignores it.
+  StartTime time.Time
+
+}
+
+// TopicMessageArgs are the arguments of Admin.SendTopicMessage
+type TopicMessageArgs struct {
+  TopicID string
+  Message string
+}
+
+// NodeStatus is the reply of Admin.NodeStatus
+type NodeStatus struct {
+  AccountID string
+  Network string
+  MirrorNode string
+
+  // Unix timestamp of the last successful hive cycle synchronization, or 0
+  // if none has completed yet
+  HiveCycle uint64
+}
+
+// AccountTransferArgs are the arguments of Admin.AccountTransfer
+type AccountTransferArgs struct {
+  To string
+  Amount string
+}
+
+// Service implements the Admin.* RPC methods on top of a Manager
+type Service struct {
+  manager Manager
+}
+
+// AddTopic subscribes the daemon to an additional HCS topic
+func (service *Service) AddTopic(args AddTopicArgs, reply *string) error {
+
+  if err := service.manager.AddTopic(args.TopicID, args.StartTime); err != nil {
+    return err
+  }
+
+  *reply = args.TopicID
+
+  return nil
+
+}
+
+// RemoveTopic cancels an active HCS topic subscription
+func (service *Service) RemoveTopic(topicID string, reply *bool) error {
+
+  if err := service.manager.RemoveTopic(topicID); err != nil {
+    return err
+  }
+
+  *reply = true
+
+  return nil
+
+}
+
+// ListTopics returns the HCS topic IDs the daemon is currently subscribed to
+func (service *Service) ListTopics(args struct{}, reply *[]string) error {
+
+  *reply = service.manager.ListTopics()
+
+  return nil
+
+}
+
+// AddJobTopic subscribes the daemon to an additional render job topic
+func (service *Service) AddJobTopic(args AddTopicArgs, reply *string) error {
+
+  if err := service.manager.AddJobTopic(args.TopicID); err != nil {
+    return err
+  }
+
+  *reply = args.TopicID
+
+  return nil
+
+}
+
+// RemoveJobTopic cancels a render job topic subscription
+func (service *Service) RemoveJobTopic(topicID string, reply *bool) error {
+
+  if err := service.manager.RemoveJobTopic(topicID); err != nil {
+    return err
+  }
+
+  *reply = true
+
+  return nil
+
+}
+
+// ResetTopicCheckpoint clears the persisted checkpoint for a topic
+func (service *Service) ResetTopicCheckpoint(topicID string, reply *bool) error {
+
+  if err := service.manager.ResetTopicCheckpoint(topicID); err != nil {
+    return err
+  }
+
+  *reply = true
+
+  return nil
+
+}
+
+// SendTopicMessage submits a message to an HCS topic and returns the Hedera
+// transaction ID
+func (service *Service) SendTopicMessage(args TopicMessageArgs, reply *string) error {
+
+  transactionID, err := service.manager.SendTopicMessage(args.TopicID, []byte(args.Message))
+  if err != nil {
+    return err
+  }
+
+  *reply = transactionID
+
+  return nil
+
+}
+
+// NodeStatus reports the basic status of the running daemon
+func (service *Service) NodeStatus(args struct{}, reply *NodeStatus) error {
+
+  *reply = service.manager.NodeStatus()
+
+  return nil
+
+}
+
+// SubmitJob reads a job description from disk and submits it to the render
+// job queue topic
+func (service *Service) SubmitJob(jobFilePath string, reply *string) error {
+
+  jobID, err := service.manager.SubmitJob(jobFilePath)
+  if err != nil {
+    return err
+  }
+
+  *reply = jobID
+
+  return nil
+
+}
+
+// ListJobs returns the render jobs known to the daemon
+func (service *Service) ListJobs(args struct{}, reply *[]string) error {
+
+  *reply = service.manager.ListJobs()
+
+  return nil
+
+}
+
+// CancelJob cancels a render job
+func (service *Service) CancelJob(jobID string, reply *bool) error {
+
+  if err := service.manager.CancelJob(jobID); err != nil {
+    return err
+  }
+
+  *reply = true
+
+  return nil
+
+}
+
+// AccountBalance returns the HBAR balance of the node operator's account
+func (service *Service) AccountBalance(args struct{}, reply *string) error {
+
+  balance, err := service.manager.AccountBalance()
+  if err != nil {
+    return err
+  }
+
+  *reply = balance
+
+  return nil
+
+}
+
+// AccountTransfer transfers HBAR from the node operator's account to another
+// account
+func (service *Service) AccountTransfer(args AccountTransferArgs, reply *string) error {
+
+  transactionID, err := service.manager.AccountTransfer(args.To, args.Amount)
+  if err != nil {
+    return err
+  }
+
+  *reply = transactionID
+
+  return nil
+
+}
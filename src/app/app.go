@@ -0,0 +1,493 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file contains all functions and other declarations for the service app.
+
+*/
+
+import (
+
+  // standard
+  "context"
+  "fmt"
+  // "os"
+  "path/filepath"
+  "time"
+  "sync"
+
+  // external
+  "github.com/getsentry/sentry-go"
+  "github.com/prometheus/client_golang/prometheus/promhttp"
+
+  // internal
+  . "renderhive/constants"
+  "renderhive/admin"
+  "renderhive/events"
+  "renderhive/logger"
+  "renderhive/node"
+  "renderhive/hedera"
+  "renderhive/ipfs"
+  "renderhive/renderer"
+  "renderhive/scheduler"
+  "renderhive/store"
+  "renderhive/webapp"
+)
+
+
+
+// STRUCTURES
+// #############################################################################
+// Configuration required to start a ServiceApp instance
+// (this replaces the values that used to be hard-coded in Init, so that the
+// CLI and other embedders can override them)
+type ServiceAppConfig struct {
+
+  // the Hedera network to connect to (testnet, mainnet, previewnet)
+  Network hedera.NetworkType
+
+  // path to the .env file holding the Hedera account credentials
+  EnvFilePath string
+
+  // address of the IPFS HTTP API to connect to
+  IPFSAPIAddress string
+
+  // log level chosen on the command line; Init stores it in Config but does
+  // not apply it to the logger
+  LogLevel string
+
+  // directory used for node data, checkpoints, and other local state
+  DataDir string
+
+  // Unix socket path the admin RPC server binds to
+  AdminSocketPath string
+
+  // URL of the MQTT broker the event bus should publish to, e.g.
+  // "tcp://localhost:1883"; left empty, the event bus stays in-process
+  MQTTBrokerURL string
+
+  // Sentry DSN crash and error reports are sent to; left empty, Sentry stays
+  // disabled
+  SentryDSN string
+
+  // maximum age a hive-cycle sync may have before /readyz reports unready
+  HiveCycleStalenessThreshold time.Duration
+
+}
+
+// DefaultAdminSocketPath is where the admin RPC server binds unless
+// overridden via ServiceAppConfig.AdminSocketPath
+const DefaultAdminSocketPath = "/tmp/renderhive.sock"
+
+// DefaultServiceAppConfig returns the configuration the service app used to
+// hard-code before it became configurable via the CLI
+func DefaultServiceAppConfig() ServiceAppConfig {
+
+  return ServiceAppConfig{
+    Network: hedera.NETWORK_TYPE_TESTNET,
+    EnvFilePath: "hedera/testnet.env",
+    IPFSAPIAddress: "",
+    LogLevel: "info",
+    DataDir: "",
+    AdminSocketPath: DefaultAdminSocketPath,
+    HiveCycleStalenessThreshold: 10 * time.Minute,
+  }
+
+}
+
+// Data required to manage the nodes
+type ServiceApp struct {
+
+  // the configuration the app was started with
+  Config ServiceAppConfig
+
+
+  // Managers
+  NodeManager *node.NodeManager
+  HederaManager *hedera.HederaManager
+  IPFSManager *ipfs.IPFSManager
+  RenderManager *renderer.RenderManager
+  WebAppManager *webapp.WebAppManager
+
+  // Hedera consensus service topics
+  // Hive cycle topics
+  HiveCycleSynchronizationTopic hedera.HederaTopic
+  HiveCycleApplicationTopic hedera.HederaTopic
+  HiveCycleValidationTopic hedera.HederaTopic
+
+  // Render job topics
+  JobQueueTopic hedera.HederaTopic
+  JobTopics []hedera.HederaTopic
+
+  // Active HCS topic subscriptions, keyed by topic ID, so they can be added
+  // or removed at runtime via the admin RPC server
+  Subscriptions map[string]*subscription
+  subscriptionsMutex sync.Mutex
+
+  // render jobs submitted through the admin RPC server, keyed by the Hedera
+  // transaction ID they were submitted under
+  Jobs map[string]*Job
+  jobsMutex sync.Mutex
+
+  // admin RPC server (node/topic/job/account management over a Unix socket)
+  AdminServer *admin.Server
+
+  // event bus every inbound HCS message is published to as a CloudEvents
+  // envelope
+  EventBus events.Bus
+
+  // checkpoint store that makes topic subscriptions resumable across restarts
+  CheckpointStore store.CheckpointStore
+  checkpointTimestamps map[string]time.Time
+  checkpointMutex sync.Mutex
+
+  // timestamp of the last successful hive cycle synchronization, used by
+  // /readyz to detect a stalled hive cycle loop; it is written from the
+  // scheduled hive-cycle-sync task and read from the /readyz HTTP handler, so
+  // every access goes through setLastHiveCycleSync/getLastHiveCycleSync
+  lastHiveCycleSync time.Time
+  lastHiveCycleSyncMutex sync.Mutex
+
+  // runs the hive-cycle sync, checkpoint-flush, and (eventually) job-dispatch
+  // tasks on a single shared WG/ctx, so DeInit only has to cancel once
+  Scheduler *scheduler.Scheduler
+
+  // Shutdown signaling: ctx is cancelled in DeInit, and every scheduled task
+  // and topic subscription started in Init checks ctx.Done() instead of a
+  // single-use channel, so any number of them can shut down without blocking
+  // each other; the admin RPC server stops when its listener is closed
+  ctx context.Context
+  cancel context.CancelFunc
+  WG sync.WaitGroup
+
+}
+
+// setLastHiveCycleSync records the time of a successful hive cycle
+// synchronization; safe to call from the scheduled hive-cycle-sync task
+// concurrently with /readyz reading it
+func (service *ServiceApp) setLastHiveCycleSync(timestamp time.Time) {
+  service.lastHiveCycleSyncMutex.Lock()
+  defer service.lastHiveCycleSyncMutex.Unlock()
+  service.lastHiveCycleSync = timestamp
+}
+
+// getLastHiveCycleSync returns the time of the last successful hive cycle
+// synchronization, or the zero value if none has completed yet
+func (service *ServiceApp) getLastHiveCycleSync() time.Time {
+  service.lastHiveCycleSyncMutex.Lock()
+  defer service.lastHiveCycleSyncMutex.Unlock()
+  return service.lastHiveCycleSync
+}
+
+
+// FUNCTIONS
+// #############################################################################
+// Initialize the Renderhive Service App session
+func (service *ServiceApp) Init(config ServiceAppConfig) (error) {
+    var err error
+
+    // remember the configuration this instance was started with
+    service.Config = config
+
+    // derive the context every background goroutine shuts down on, and the
+    // scheduler every periodic task is registered with
+    service.ctx, service.cancel = context.WithCancel(context.Background())
+    service.Scheduler = scheduler.New(service.ctx, &service.WG)
+
+    // log the start of the renderhive service
+    logger.RenderhiveLogger.Main.Info().Msg("Starting Renderhive service app.")
+
+    // INITIALIZE ERROR REPORTING
+    // *************************************************************************
+    // send panics from subscription callbacks and the hive-cycle loop to
+    // Sentry instead of losing them to zerolog output
+    if service.Config.SentryDSN != "" {
+      err = sentry.Init(sentry.ClientOptions{Dsn: service.Config.SentryDSN})
+      if err != nil {
+        return err
+      }
+    }
+
+    // INITIALIZE INTERNAL MANAGERS
+    // *************************************************************************
+    // initialize the node manager
+    service.NodeManager = &node.NodeManager{}
+    err = service.NodeManager.Init()
+    if err != nil {
+      return err
+    }
+
+    // initialize the Hedera manager
+    service.HederaManager = &hedera.HederaManager{}
+    err = service.HederaManager.Init(service.Config.Network, service.Config.EnvFilePath)
+    if err != nil {
+      return err
+    }
+    logger.RenderhiveLogger.Main.Info().Msg("Loaded the account details from the environment file.")
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Public key: %s", service.HederaManager.Operator.PublicKey))
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf("Mirror node: %v", service.HederaManager.MirrorNode.URL))
+
+    // initialize the IPFS manager
+    service.IPFSManager = &ipfs.IPFSManager{}
+    err = service.IPFSManager.Init(service.Config.IPFSAPIAddress)
+    if err != nil {
+      return err
+    }
+
+    // initialize the render manager
+    service.RenderManager = &renderer.RenderManager{}
+    err = service.RenderManager.Init()
+    if err != nil {
+      return err
+    }
+
+    // initialize the web app manager
+    service.WebAppManager = &webapp.WebAppManager{}
+    err = service.WebAppManager.Init()
+    if err != nil {
+      return err
+    }
+
+    // CHECKPOINT STORE
+    // *************************************************************************
+    // resume HCS topic subscriptions from where they left off instead of
+    // replaying every message since the beginning of the topic
+    service.CheckpointStore, err = store.NewBoltCheckpointStore(filepath.Join(service.Config.DataDir, "checkpoints.db"))
+    if err != nil {
+      return err
+    }
+    service.Scheduler.Register(service.checkpointFlushTask())
+
+    // EVENT BUS
+    // *************************************************************************
+    // wrap every inbound HCS message in a CloudEvents envelope and publish it;
+    // default to an in-process bus, optionally pointed at an MQTT broker
+    if service.Config.MQTTBrokerURL != "" {
+      service.EventBus, err = events.NewMQTTBus(service.Config.MQTTBrokerURL, fmt.Sprintf("renderhive-%v", service.HederaManager.Operator.AccountID), "renderhive/events")
+      if err != nil {
+        return err
+      }
+    } else {
+      service.EventBus = events.NewMemoryBus(256)
+    }
+
+    // accept CloudEvents job-submission events from external tools and
+    // forward them to the HCS topic they name
+    service.WebAppManager.RegisterHandler("/events", events.IngressHandler(service))
+
+    // HEALTH & READINESS
+    // *************************************************************************
+    // let orchestrators probe whether the daemon and its dependencies are up
+    service.WebAppManager.RegisterHandler("/healthz", service.healthzHandler())
+    service.WebAppManager.RegisterHandler("/readyz", service.readyzHandler())
+
+    // expose the scheduler's task metrics for scraping
+    service.WebAppManager.RegisterHandler("/metrics", promhttp.Handler())
+
+    // READ HCS TOPIC INFORMATION & SUBSCRIBE
+    // *************************************************************************
+    // render job queue: goes through AddJobTopic (not subscribeTopic
+    // directly) so it is also tracked in JobTopics like any job topic added
+    // later via the admin RPC server
+    err = service.AddJobTopic(RENDERHIVE_TESTNET_RENDER_JOB_QUEUE)
+    if err != nil {
+      return err
+    }
+
+    // hive cycle synchronization topic
+    _, err = service.subscribeTopic(RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_SYNCHRONIZATION, time.Unix(0, 0), events.EventTypeHiveCycleSync, service.NodeManager.HiveCycle.MessageCallback(), false)
+    if err != nil {
+      return err
+    }
+
+    // hive cycle application topic
+    _, err = service.subscribeTopic(RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_APPLICATION, time.Unix(0, 0), events.EventTypeHiveCycleApplication, defaultTopicCallback, false)
+    if err != nil {
+      return err
+    }
+
+    // hive cycle validation topic
+    _, err = service.subscribeTopic(RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_VALIDATION, time.Unix(0, 0), events.EventTypeHiveCycleValidation, defaultTopicCallback, false)
+    if err != nil {
+      return err
+    }
+
+    // ADMIN RPC SERVER
+    // *************************************************************************
+    // expose Admin.AddTopic/Admin.RemoveTopic/... on a Unix socket so topic
+    // and job-topic subscriptions can be managed without restarting the daemon
+    service.AdminServer, err = admin.NewServer(service.Config.AdminSocketPath, service)
+    if err != nil {
+      return err
+    }
+    err = service.AdminServer.Start()
+    if err != nil {
+      return err
+    }
+
+
+
+    // HIVE CYCLE
+    // *************************************************************************
+    // synchronize with the render hive once up front, then keep doing so as a
+    // scheduled task: a failed Synchronize backs off with jitter instead of
+    // busy-looping, and a panic is recovered and reported to Sentry the same
+    // way any other scheduled task's panic is
+    service.NodeManager.HiveCycle.Synchronize(service.HederaManager)
+    service.setLastHiveCycleSync(time.Now())
+    scheduler.HiveCycleCurrent.Set(float64(service.getLastHiveCycleSync().Unix()))
+
+    service.Scheduler.Register(scheduler.PeriodicTask{
+      Name: "hive-cycle-sync",
+      NextRun: func() time.Duration {
+        configurations := service.NodeManager.HiveCycle.Configurations
+        return time.Duration(configurations[len(configurations)-1].Duration/10) * time.Second
+      },
+      Run: func(ctx context.Context) error {
+        service.NodeManager.HiveCycle.Synchronize(service.HederaManager)
+        service.setLastHiveCycleSync(time.Now())
+        scheduler.HiveCycleCurrent.Set(float64(service.getLastHiveCycleSync().Unix()))
+        return nil
+      },
+    })
+
+    // job dispatch does not have its own task yet; once it does, register it
+    // here alongside hive-cycle-sync and checkpoint-flush so it shares the
+    // same Scheduler, WG, and shutdown context
+
+
+
+    // STATE CHECKS
+    // *************************************************************************
+    // perform important state checks
+    // ...
+
+
+
+
+    // LOG BASIC APP INFORMATION
+    // *************************************************************************
+
+    // log some informations about the used constants
+    logger.RenderhiveLogger.Main.Info().Msg("This service app instance relies on the following smart contract(s) and HCS topic(s):")
+    // the renderhive smart contract this instance calls
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Smart Contract: %s", RENDERHIVE_TESTNET_SMART_CONTRACT))
+    // Hive cycle
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Hive Cycle Synchronization Topic: %s", RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_SYNCHRONIZATION))
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Hive Cycle Application Topic: %s", RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_APPLICATION))
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Hive Cycle Validation Topic: %s", RENDERHIVE_TESTNET_TOPIC_HIVE_CYCLE_VALIDATION))
+    // Render jobs
+    logger.RenderhiveLogger.Main.Info().Msg(fmt.Sprintf(" [#] Render Job Topic: %s", RENDERHIVE_TESTNET_RENDER_JOB_QUEUE))
+
+
+    return nil
+}
+
+// Deinitialize the Renderhive Service App session
+func (service *ServiceApp) DeInit() (error) {
+    var err error
+
+    // log event
+    logger.RenderhiveLogger.Main.Info().Msg("Stopping Renderhive service app ... ")
+
+    // cancel the shutdown context; every background goroutine selecting on
+    // ctx.Done() observes this, however many of them are running
+    service.cancel()
+
+    // log event
+    logger.RenderhiveLogger.Main.Info().Msg("Waiting for background operations to shut down ... ")
+    service.WG.Wait()
+
+    // DEINITIALIZE INTERNAL MANAGERS
+    // *************************************************************************
+
+    // stop the admin RPC server
+    err = service.AdminServer.Stop()
+    if err != nil {
+      return err
+    }
+
+    // cancel every active HCS topic subscription and tear down its mirror
+    // node stream; this must happen before the event bus and checkpoint
+    // store are closed below, since a message still in flight would
+    // otherwise publish on a closed bus or write to a closed store
+    service.unsubscribeAll()
+
+    // close the event bus
+    err = service.EventBus.Close()
+    if err != nil {
+      return err
+    }
+
+    // flush any checkpoint progress the scheduled task has not written yet
+    err = service.flushCheckpoints()
+    if err != nil {
+      return err
+    }
+
+    // close the checkpoint store
+    err = service.CheckpointStore.Close()
+    if err != nil {
+      return err
+    }
+
+    // deinitialize the web app manager
+    err = service.WebAppManager.DeInit()
+    if err != nil {
+      return err
+    }
+
+    // deinitialize the render manager
+    err = service.RenderManager.DeInit()
+    if err != nil {
+      return err
+    }
+
+    // deinitialize the IPFS manager
+    service.IPFSManager.DeInit()
+    if err != nil {
+      return err
+    }
+
+    // deinitialize the Hedera manager
+    err = service.HederaManager.DeInit()
+    if err != nil {
+      return err
+    }
+
+    // deinitialize the node manager
+    err = service.NodeManager.DeInit()
+    if err != nil {
+      return err
+    }
+
+
+
+    // LOG BASIC APP INFORMATION
+    // *************************************************************************
+
+    logger.RenderhiveLogger.Main.Info().Msg("Renderhive service app stopped.")
+
+    return err
+
+}
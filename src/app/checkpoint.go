@@ -0,0 +1,145 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file makes HCS topic subscriptions resumable: it resolves the start time
+of a subscription from the CheckpointStore instead of always starting at
+time.Unix(0, 0), records the consensus timestamp of every message that was
+published and handled by its subscriber callback without error, and
+periodically flushes those timestamps to
+the CheckpointStore in the background.
+
+*/
+
+import (
+
+  // standard
+  "context"
+  "fmt"
+  "time"
+
+  // internal
+  "renderhive/scheduler"
+)
+
+// CheckpointFlushInterval is how often in-memory checkpoint progress is
+// written to the CheckpointStore
+const CheckpointFlushInterval = 5 * time.Second
+
+// nodeAccount returns the operator account ID checkpoints are keyed under
+func (service *ServiceApp) nodeAccount() string {
+  return fmt.Sprintf("%v", service.HederaManager.Operator.AccountID)
+}
+
+// resumeStartTime returns the time a topic subscription should resume from:
+// the last checkpointed consensus timestamp plus one nanosecond, or
+// fallbackStart if no checkpoint has been recorded yet
+func (service *ServiceApp) resumeStartTime(topicIDString string, fallbackStart time.Time) (time.Time, error) {
+
+  if service.CheckpointStore == nil {
+    return fallbackStart, nil
+  }
+
+  last, found, err := service.CheckpointStore.Get(service.nodeAccount(), topicIDString)
+  if err != nil {
+    return fallbackStart, err
+  }
+  if !found {
+    return fallbackStart, nil
+  }
+
+  return last.Add(time.Nanosecond), nil
+
+}
+
+// recordCheckpoint remembers the consensus timestamp of the most recently
+// handled message for a topic; it is flushed to the CheckpointStore by
+// flushCheckpoints
+func (service *ServiceApp) recordCheckpoint(topicIDString string, timestamp time.Time) {
+
+  service.checkpointMutex.Lock()
+  defer service.checkpointMutex.Unlock()
+
+  if service.checkpointTimestamps == nil {
+    service.checkpointTimestamps = make(map[string]time.Time)
+  }
+  service.checkpointTimestamps[topicIDString] = timestamp
+
+}
+
+// flushCheckpoints writes every recorded timestamp to the CheckpointStore
+func (service *ServiceApp) flushCheckpoints() error {
+
+  if service.CheckpointStore == nil {
+    return nil
+  }
+
+  service.checkpointMutex.Lock()
+  pending := make(map[string]time.Time, len(service.checkpointTimestamps))
+  for topicIDString, timestamp := range service.checkpointTimestamps {
+    pending[topicIDString] = timestamp
+  }
+  service.checkpointMutex.Unlock()
+
+  nodeAccount := service.nodeAccount()
+  for topicIDString, timestamp := range pending {
+    if err := service.CheckpointStore.Set(nodeAccount, topicIDString, timestamp); err != nil {
+      return err
+    }
+  }
+
+  return nil
+
+}
+
+// ResetTopicCheckpoint clears the checkpoint for a topic, so the next time it
+// is subscribed to it is replayed from the beginning (e.g. for reindexing)
+func (service *ServiceApp) ResetTopicCheckpoint(topicIDString string) error {
+
+  if service.CheckpointStore == nil {
+    return fmt.Errorf("no checkpoint store configured")
+  }
+
+  service.checkpointMutex.Lock()
+  delete(service.checkpointTimestamps, topicIDString)
+  service.checkpointMutex.Unlock()
+
+  return service.CheckpointStore.Reset(service.nodeAccount(), topicIDString)
+
+}
+
+// checkpointFlushTask is the scheduled task that periodically flushes
+// checkpoint progress to the CheckpointStore; DeInit also flushes once more
+// directly after the scheduler stops, so nothing is lost between the last
+// run and shutdown
+func (service *ServiceApp) checkpointFlushTask() scheduler.PeriodicTask {
+
+  return scheduler.PeriodicTask{
+    Name: "checkpoint-flush",
+    NextRun: func() time.Duration { return CheckpointFlushInterval },
+    Run: func(ctx context.Context) error {
+      return service.flushCheckpoints()
+    },
+  }
+
+}
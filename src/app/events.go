@@ -0,0 +1,40 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file makes ServiceApp satisfy events.Forwarder, so the CloudEvents
+ingress endpoint can forward an externally submitted event to the HCS topic
+it names.
+
+*/
+
+// SubmitTopicMessage submits a raw payload to the given HCS topic. It
+// implements events.Forwarder; the ingress endpoint does not need the
+// transaction ID SendTopicMessage returns, so it is discarded here.
+func (service *ServiceApp) SubmitTopicMessage(topicIDString string, payload []byte) error {
+
+  _, err := service.SendTopicMessage(topicIDString, payload)
+
+  return err
+
+}
@@ -0,0 +1,156 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file implements the /healthz and /readyz endpoints the WebAppManager
+serves. /healthz only reports that the process is alive; /readyz additionally
+checks the health of every manager the daemon depends on.
+
+*/
+
+import (
+
+  // standard
+  "encoding/json"
+  "net/http"
+  "time"
+
+)
+
+// ManagerHealth is the readiness of a single dependency
+type ManagerHealth struct {
+  Name string `json:"name"`
+  Ready bool `json:"ready"`
+  Detail string `json:"detail,omitempty"`
+}
+
+// ReadinessReport is the body returned by /readyz
+type ReadinessReport struct {
+  Ready bool `json:"ready"`
+  Managers []ManagerHealth `json:"managers"`
+}
+
+// healthzHandler reports that the process is alive and serving requests
+func (service *ServiceApp) healthzHandler() http.Handler {
+
+  return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    w.WriteHeader(http.StatusOK)
+  })
+
+}
+
+// readyzHandler reports whether the daemon and its dependencies are ready to
+// serve traffic: the Hedera mirror node is reachable, the IPFS daemon is
+// reachable, and the last hive cycle sync is not stale
+func (service *ServiceApp) readyzHandler() http.Handler {
+
+  return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+    report := ReadinessReport{Ready: true}
+
+    for _, check := range []ManagerHealth{
+      service.checkMirrorNode(),
+      service.checkIPFS(),
+      service.checkHiveCycle(),
+    } {
+      report.Managers = append(report.Managers, check)
+      if !check.Ready {
+        report.Ready = false
+      }
+    }
+
+    if !report.Ready {
+      w.WriteHeader(http.StatusServiceUnavailable)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(report)
+
+  })
+
+}
+
+// checkMirrorNode reports whether the configured Hedera mirror node responds
+func (service *ServiceApp) checkMirrorNode() ManagerHealth {
+
+  health := ManagerHealth{Name: "hedera-mirror-node"}
+
+  client := http.Client{Timeout: 5 * time.Second}
+  resp, err := client.Get(service.HederaManager.MirrorNode.URL)
+  if err != nil {
+    health.Detail = err.Error()
+    return health
+  }
+  defer resp.Body.Close()
+
+  health.Ready = resp.StatusCode < 500
+
+  return health
+
+}
+
+// checkIPFS reports whether the configured IPFS API responds
+func (service *ServiceApp) checkIPFS() ManagerHealth {
+
+  health := ManagerHealth{Name: "ipfs"}
+
+  if service.Config.IPFSAPIAddress == "" {
+    health.Ready = true
+    health.Detail = "no IPFS API address configured"
+    return health
+  }
+
+  client := http.Client{Timeout: 5 * time.Second}
+  resp, err := client.Post(service.Config.IPFSAPIAddress+"/api/v0/id", "", nil)
+  if err != nil {
+    health.Detail = err.Error()
+    return health
+  }
+  defer resp.Body.Close()
+
+  health.Ready = resp.StatusCode < 500
+
+  return health
+
+}
+
+// checkHiveCycle reports whether the hive cycle has synchronized recently
+func (service *ServiceApp) checkHiveCycle() ManagerHealth {
+
+  health := ManagerHealth{Name: "hive-cycle"}
+
+  lastSync := service.getLastHiveCycleSync()
+  if lastSync.IsZero() {
+    health.Detail = "no hive cycle synchronization has completed yet"
+    return health
+  }
+
+  age := time.Since(lastSync)
+  health.Ready = age < service.Config.HiveCycleStalenessThreshold
+  if !health.Ready {
+    health.Detail = "last synchronization was " + age.String() + " ago"
+  }
+
+  return health
+
+}
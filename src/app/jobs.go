@@ -0,0 +1,119 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file implements `renderhive job submit|list|cancel` on top of a minimal
+in-memory job registry. There is no render-dispatch engine in this tree yet
+(no job-dispatch task is registered with the Scheduler), so a job
+is only "submitted" in the sense that its description reached the render job
+queue topic; Status is local bookkeeping until a real dispatcher exists to
+advance it beyond JobStatusQueued/JobStatusCancelled.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+  "os"
+  "time"
+
+  // internal
+  . "renderhive/constants"
+)
+
+// JobStatus is the lifecycle state of a locally tracked render job
+type JobStatus string
+
+const (
+  JobStatusQueued JobStatus = "queued"
+  JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is the local bookkeeping record for a render job submitted through the
+// admin RPC server
+type Job struct {
+  ID string
+  Status JobStatus
+  SubmittedAt time.Time
+}
+
+// SubmitJob reads a job description from disk and submits it to the render
+// job queue topic. It implements admin.Manager.
+func (service *ServiceApp) SubmitJob(jobFilePath string) (string, error) {
+
+  payload, err := os.ReadFile(jobFilePath)
+  if err != nil {
+    return "", err
+  }
+
+  transactionID, err := service.SendTopicMessage(RENDERHIVE_TESTNET_RENDER_JOB_QUEUE, payload)
+  if err != nil {
+    return "", err
+  }
+
+  service.jobsMutex.Lock()
+  if service.Jobs == nil {
+    service.Jobs = make(map[string]*Job)
+  }
+  service.Jobs[transactionID] = &Job{ID: transactionID, Status: JobStatusQueued, SubmittedAt: time.Now()}
+  service.jobsMutex.Unlock()
+
+  return transactionID, nil
+
+}
+
+// ListJobs returns the locally tracked render jobs as "<id>\t<status>"
+// lines. It implements admin.Manager.
+func (service *ServiceApp) ListJobs() []string {
+
+  service.jobsMutex.Lock()
+  defer service.jobsMutex.Unlock()
+
+  jobs := make([]string, 0, len(service.Jobs))
+  for _, job := range service.Jobs {
+    jobs = append(jobs, fmt.Sprintf("%s\t%s", job.ID, job.Status))
+  }
+
+  return jobs
+
+}
+
+// CancelJob marks a locally tracked render job as cancelled. It implements
+// admin.Manager. There is no dispatcher yet to stop an in-progress render;
+// this only stops the daemon from reporting the job as queued.
+func (service *ServiceApp) CancelJob(jobID string) error {
+
+  service.jobsMutex.Lock()
+  defer service.jobsMutex.Unlock()
+
+  job, ok := service.Jobs[jobID]
+  if !ok {
+    return fmt.Errorf("unknown job %s", jobID)
+  }
+
+  job.Status = JobStatusCancelled
+
+  return nil
+
+}
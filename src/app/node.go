@@ -0,0 +1,72 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file implements the ServiceApp side of the `renderhive node` and
+`renderhive account` admin RPC methods: node status, and inspecting/moving
+the node operator's HBAR balance.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+
+  // internal
+  "renderhive/admin"
+)
+
+// NodeStatus reports the basic status of the running daemon. It implements
+// admin.Manager.
+func (service *ServiceApp) NodeStatus() admin.NodeStatus {
+
+  var hiveCycle uint64
+  if lastSync := service.getLastHiveCycleSync(); !lastSync.IsZero() {
+    hiveCycle = uint64(lastSync.Unix())
+  }
+
+  return admin.NodeStatus{
+    AccountID: fmt.Sprintf("%v", service.HederaManager.Operator.AccountID),
+    Network: fmt.Sprintf("%v", service.Config.Network),
+    MirrorNode: service.HederaManager.MirrorNode.URL,
+    HiveCycle: hiveCycle,
+  }
+
+}
+
+// AccountBalance returns the HBAR balance of the node operator's account. It
+// implements admin.Manager.
+func (service *ServiceApp) AccountBalance() (string, error) {
+
+  return service.HederaManager.AccountBalance(service.HederaManager.Operator.AccountID)
+
+}
+
+// AccountTransfer transfers HBAR from the node operator's account to another
+// account and returns the Hedera transaction ID. It implements admin.Manager.
+func (service *ServiceApp) AccountTransfer(toAccountID string, amount string) (string, error) {
+
+  return service.HederaManager.TransferHbar(toAccountID, amount)
+
+}
@@ -0,0 +1,257 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package app
+
+/*
+
+This file contains the ServiceApp side of the HCS topic subscriptions: it
+keeps an indexed map of active subscriptions so they can be added or removed
+at runtime (e.g. from the admin RPC server) instead of only at Init time.
+
+*/
+
+import (
+
+  // standard
+  "context"
+  "fmt"
+  "time"
+
+  // external
+  hederasdk "github.com/hashgraph/hedera-sdk-go/v2"
+  "github.com/getsentry/sentry-go"
+
+  // internal
+  "renderhive/events"
+  "renderhive/hedera"
+  "renderhive/logger"
+)
+
+// subscription is a cancellable handle for one active HCS topic subscription
+type subscription struct {
+  Topic *hedera.HederaTopic
+  IsJobTopic bool
+  handle hederasdk.SubscriptionHandle
+  cancel context.CancelFunc
+}
+
+// defaultTopicCallback logs the raw contents of a message, the same way the
+// four bootstrap subscriptions in Init used to
+func defaultTopicCallback(message hederasdk.TopicMessage) error {
+  logger.RenderhiveLogger.Package["hedera"].Info().Msg(fmt.Sprintf("Message received: %s", string(message.Contents)))
+  return nil
+}
+
+// subscribeTopic resolves a topic ID, subscribes to it via the Hedera
+// manager, and registers the resulting handle so it can be cancelled again.
+// TopicSubscribe returns the hederasdk.SubscriptionHandle for the mirror node
+// stream itself, so cancelling a subscription actually tears down the stream
+// instead of merely muting the callback. Every message is also wrapped in a
+// CloudEvents envelope and published on service.EventBus before the callback
+// runs. If a CheckpointStore is configured, the subscription resumes from the
+// last checkpointed consensus timestamp instead of startTime; the checkpoint
+// only advances once both the publish and callback for a message succeed, so
+// a failure on either leaves the message to be replayed on restart.
+func (service *ServiceApp) subscribeTopic(topicIDString string, startTime time.Time, eventType string, callback func(hederasdk.TopicMessage) error, isJobTopic bool) (*hedera.HederaTopic, error) {
+
+  topic, err := service.HederaManager.TopicInfoFromString(topicIDString)
+  if err != nil {
+    return nil, err
+  }
+
+  resumeStart, err := service.resumeStartTime(topicIDString, startTime)
+  if err != nil {
+    return nil, err
+  }
+
+  ctx, cancel := context.WithCancel(service.ctx)
+
+  handle, err := service.HederaManager.TopicSubscribe(topic, resumeStart, func(message hederasdk.TopicMessage) {
+
+    select {
+    case <-ctx.Done():
+      return
+    default:
+    }
+
+    // recover from panics in the callback (and in Publish) so a single bad
+    // message cannot take the whole daemon down; report it to Sentry with
+    // the topic and node tagged for triage
+    defer func() {
+      if r := recover(); r != nil {
+        sentry.WithScope(func(scope *sentry.Scope) {
+          scope.SetTag("topic", topicIDString)
+          scope.SetTag("node", message.NodeID.String())
+          sentry.CaptureException(fmt.Errorf("panic in topic subscription callback: %v", r))
+        })
+        logger.RenderhiveLogger.Package["hedera"].Error().Msg(fmt.Sprintf("Recovered from panic while handling message on topic %s: %v", topicIDString, r))
+      }
+    }()
+
+    published := true
+    if service.EventBus != nil {
+      envelope := events.NewEnvelope(topicIDString, eventType, message.ConsensusTimestamp, message.NodeID.String(), message.Contents)
+      if err := service.EventBus.Publish(ctx, envelope); err != nil {
+        published = false
+        logger.RenderhiveLogger.Package["hedera"].Warn().Msg(fmt.Sprintf("Could not publish event for topic %s: %v", topicIDString, err))
+      }
+    }
+
+    if err := callback(message); err != nil {
+      logger.RenderhiveLogger.Package["hedera"].Warn().Msg(fmt.Sprintf("Could not process message on topic %s: %v", topicIDString, err))
+      return
+    }
+
+    if !published {
+      return
+    }
+
+    // the checkpoint only advances once the message has been both published
+    // and processed without error; this only advances the in-memory
+    // checkpoint, the durable write happens in flushCheckpoints
+    service.recordCheckpoint(topicIDString, message.ConsensusTimestamp)
+
+  })
+  if err != nil {
+    cancel()
+    return nil, err
+  }
+
+  service.subscriptionsMutex.Lock()
+  defer service.subscriptionsMutex.Unlock()
+
+  if service.Subscriptions == nil {
+    service.Subscriptions = make(map[string]*subscription)
+  }
+  service.Subscriptions[topicIDString] = &subscription{Topic: topic, IsJobTopic: isJobTopic, handle: handle, cancel: cancel}
+
+  return topic, nil
+
+}
+
+// unsubscribeAll cancels every active subscription and tears down its
+// underlying mirror node stream. DeInit must call this before it closes
+// EventBus and CheckpointStore, since a message that is still in flight when
+// those close would publish on a closed bus or write to a closed store.
+func (service *ServiceApp) unsubscribeAll() {
+
+  service.subscriptionsMutex.Lock()
+  defer service.subscriptionsMutex.Unlock()
+
+  for _, sub := range service.Subscriptions {
+    sub.cancel()
+    sub.handle.Unsubscribe()
+  }
+
+}
+
+// AddTopic subscribes to an additional HCS topic without restarting the
+// daemon
+func (service *ServiceApp) AddTopic(topicIDString string, startTime time.Time) error {
+
+  _, err := service.subscribeTopic(topicIDString, startTime, events.EventTypeTopicMessage, defaultTopicCallback, false)
+
+  return err
+
+}
+
+// RemoveTopic cancels an active HCS topic subscription
+func (service *ServiceApp) RemoveTopic(topicIDString string) error {
+
+  service.subscriptionsMutex.Lock()
+  defer service.subscriptionsMutex.Unlock()
+
+  sub, ok := service.Subscriptions[topicIDString]
+  if !ok {
+    return fmt.Errorf("no active subscription for topic %s", topicIDString)
+  }
+
+  sub.cancel()
+  sub.handle.Unsubscribe()
+  delete(service.Subscriptions, topicIDString)
+
+  if sub.IsJobTopic {
+    for i, jobTopic := range service.JobTopics {
+      if jobTopic == *sub.Topic {
+        service.JobTopics = append(service.JobTopics[:i], service.JobTopics[i+1:]...)
+        break
+      }
+    }
+  }
+
+  return nil
+
+}
+
+// ListTopics returns the topic IDs the daemon is currently subscribed to
+func (service *ServiceApp) ListTopics() []string {
+
+  service.subscriptionsMutex.Lock()
+  defer service.subscriptionsMutex.Unlock()
+
+  topicIDs := make([]string, 0, len(service.Subscriptions))
+  for topicIDString := range service.Subscriptions {
+    topicIDs = append(topicIDs, topicIDString)
+  }
+
+  return topicIDs
+
+}
+
+// AddJobTopic subscribes to an additional render job topic and tracks it in
+// JobTopics
+func (service *ServiceApp) AddJobTopic(topicIDString string) error {
+
+  topic, err := service.subscribeTopic(topicIDString, time.Unix(0, 0), events.EventTypeJobQueued, defaultTopicCallback, true)
+  if err != nil {
+    return err
+  }
+
+  // JobTopics is also mutated by RemoveTopic, so it is guarded by the same
+  // subscriptionsMutex as Subscriptions instead of its own lock
+  service.subscriptionsMutex.Lock()
+  service.JobTopics = append(service.JobTopics, *topic)
+  service.subscriptionsMutex.Unlock()
+
+  return nil
+
+}
+
+// RemoveJobTopic cancels a render job topic subscription
+func (service *ServiceApp) RemoveJobTopic(topicIDString string) error {
+
+  return service.RemoveTopic(topicIDString)
+
+}
+
+// SendTopicMessage submits a message to an HCS topic and returns the Hedera
+// transaction ID. It implements admin.Manager and is also used by SubmitJob
+// to put a job description on the render job queue topic.
+func (service *ServiceApp) SendTopicMessage(topicIDString string, payload []byte) (string, error) {
+
+  topic, err := service.HederaManager.TopicInfoFromString(topicIDString)
+  if err != nil {
+    return "", err
+  }
+
+  return service.HederaManager.TopicMessageSubmit(topic, payload)
+
+}
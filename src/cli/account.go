@@ -0,0 +1,104 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file implements the `renderhive account` subcommand tree for inspecting
+the node operator's Hedera account and transferring HBAR.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+
+  // internal
+  "renderhive/admin"
+)
+
+// AccountCommand is the `renderhive account` subcommand tree
+var AccountCommand = &climod.Command{
+  Name: "account",
+  Usage: "Inspect and manage the node operator's Hedera account",
+  Subcommands: []*climod.Command{
+    accountBalanceCommand,
+    accountTransferCommand,
+  },
+}
+
+var accountBalanceCommand = &climod.Command{
+  Name: "balance",
+  Usage: "Show the HBAR balance of the node operator's account",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var balance string
+    if err := client.Call(admin.RPCName+".AccountBalance", struct{}{}, &balance); err != nil {
+      return err
+    }
+
+    fmt.Println(balance)
+
+    return nil
+
+  },
+}
+
+var accountTransferCommand = &climod.Command{
+  Name: "transfer",
+  Usage: "Transfer HBAR from the node operator's account",
+  ArgsUsage: "<toAccountID> <amount>",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 2 {
+      return fmt.Errorf("expected exactly two arguments: <toAccountID> <amount>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    args := struct{ To, Amount string }{c.Args().Get(0), c.Args().Get(1)}
+    var transactionID string
+    if err := client.Call(admin.RPCName+".AccountTransfer", args, &transactionID); err != nil {
+      return err
+    }
+
+    fmt.Println(transactionID)
+
+    return nil
+
+  },
+}
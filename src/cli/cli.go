@@ -0,0 +1,199 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file defines the root `renderhive` command and the global flags that used
+to be hard-coded in ServiceApp.Init (the network, the .env file, the IPFS API
+address, the log level, and the data directory). It is modeled after the
+`cmd/geth/main.go` pattern: a root command that boots the long-running daemon,
+plus a tree of subcommands (node, topic, job, account, console) that talk to
+an already running daemon over the admin socket.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+  "os"
+  "os/signal"
+  "syscall"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+
+  // internal
+  "renderhive/app"
+  "renderhive/hedera"
+  "renderhive/logger"
+)
+
+// FLAGS
+// #############################################################################
+var (
+
+  TestnetFlag = &climod.BoolFlag{
+    Name: "testnet",
+    Usage: "Connect to the Hedera testnet (default)",
+  }
+
+  MainnetFlag = &climod.BoolFlag{
+    Name: "mainnet",
+    Usage: "Connect to the Hedera mainnet",
+  }
+
+  PreviewnetFlag = &climod.BoolFlag{
+    Name: "previewnet",
+    Usage: "Connect to the Hedera previewnet",
+  }
+
+  EnvFileFlag = &climod.StringFlag{
+    Name: "envfile",
+    Usage: "Path to the .env file with the Hedera account credentials",
+    Value: "hedera/testnet.env",
+  }
+
+  IPFSAPIFlag = &climod.StringFlag{
+    Name: "ipfs-api",
+    Usage: "Multiaddress of the IPFS API to connect to",
+  }
+
+  LogLevelFlag = &climod.StringFlag{
+    Name: "loglevel",
+    Usage: "Log level (trace, debug, info, warn, error)",
+    Value: "info",
+  }
+
+  DataDirFlag = &climod.StringFlag{
+    Name: "datadir",
+    Usage: "Directory for node data, checkpoints, and other local state",
+    Value: "",
+  }
+
+  SocketFlag = &climod.StringFlag{
+    Name: "socket",
+    Usage: "Path to the admin Unix socket of a running renderhive daemon",
+    Value: DefaultSocketPath,
+  }
+
+  MQTTBrokerFlag = &climod.StringFlag{
+    Name: "mqtt-broker",
+    Usage: "MQTT broker URL to publish CloudEvents to (e.g. tcp://localhost:1883); defaults to an in-process event bus",
+  }
+
+  SentryDSNFlag = &climod.StringFlag{
+    Name: "sentry-dsn",
+    Usage: "Sentry DSN to report panics and errors to; leave empty to disable Sentry",
+  }
+
+)
+
+// App is the root command of the renderhive binary
+var App = NewApp()
+
+// NewApp assembles the root command and its full subcommand tree
+func NewApp() *climod.App {
+
+  rootApp := &climod.App{
+    Name: "renderhive",
+    Usage: "the Renderhive Service App",
+    Flags: []climod.Flag{
+      TestnetFlag,
+      MainnetFlag,
+      PreviewnetFlag,
+      EnvFileFlag,
+      IPFSAPIFlag,
+      LogLevelFlag,
+      DataDirFlag,
+      SocketFlag,
+      MQTTBrokerFlag,
+      SentryDSNFlag,
+    },
+    Action: runDaemon,
+    Commands: []*climod.Command{
+      NodeCommand,
+      TopicCommand,
+      JobCommand,
+      AccountCommand,
+      ConsoleCommand,
+    },
+  }
+
+  return rootApp
+
+}
+
+// configFromContext turns the global flags into an app.ServiceAppConfig,
+// replacing the values that used to be hard-coded in ServiceApp.Init
+func configFromContext(c *climod.Context) app.ServiceAppConfig {
+
+  config := app.DefaultServiceAppConfig()
+
+  switch {
+
+  case c.Bool(MainnetFlag.Name):
+    config.Network = hedera.NETWORK_TYPE_MAINNET
+
+  case c.Bool(PreviewnetFlag.Name):
+    config.Network = hedera.NETWORK_TYPE_PREVIEWNET
+
+  default:
+    config.Network = hedera.NETWORK_TYPE_TESTNET
+
+  }
+
+  config.EnvFilePath = c.String(EnvFileFlag.Name)
+  config.IPFSAPIAddress = c.String(IPFSAPIFlag.Name)
+  config.LogLevel = c.String(LogLevelFlag.Name)
+  config.DataDir = c.String(DataDirFlag.Name)
+  config.AdminSocketPath = c.String(SocketFlag.Name)
+  config.MQTTBrokerURL = c.String(MQTTBrokerFlag.Name)
+  config.SentryDSN = c.String(SentryDSNFlag.Name)
+
+  return config
+
+}
+
+// runDaemon is the action of the root command: it boots every manager and
+// keeps running until it receives a shutdown signal
+func runDaemon(c *climod.Context) error {
+
+  service := &app.ServiceApp{}
+
+  if err := service.Init(configFromContext(c)); err != nil {
+    return fmt.Errorf("could not start the renderhive service app: %w", err)
+  }
+
+  logger.RenderhiveLogger.Main.Info().Msg("Renderhive daemon is running. Press Ctrl+C to stop.")
+
+  // block until a shutdown signal arrives, then give every background
+  // goroutine a chance to drain via ServiceApp.DeInit
+  quit := make(chan os.Signal, 1)
+  signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+  <-quit
+
+  logger.RenderhiveLogger.Main.Info().Msg("Shutdown signal received.")
+
+  return service.DeInit()
+
+}
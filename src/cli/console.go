@@ -0,0 +1,107 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file implements `renderhive console`, an interactive REPL that connects
+to a running daemon's admin socket and exposes the same `node`, `topic`,
+`job`, and `account` commands one-at-a-time instead of one process per
+invocation.
+
+*/
+
+import (
+
+  // standard
+  "bufio"
+  "fmt"
+  "os"
+  "strings"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+)
+
+// ConsoleCommand starts the interactive console
+var ConsoleCommand = &climod.Command{
+  Name: "console",
+  Usage: "Start an interactive console connected to a running renderhive daemon",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    socketPath := c.String(SocketFlag.Name)
+
+    // fail fast if no daemon is listening, rather than after the first command
+    client, err := dialAdmin(socketPath)
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    client.Close()
+
+    fmt.Println("Welcome to the renderhive console. Type \"exit\" to quit.")
+
+    scanner := bufio.NewScanner(os.Stdin)
+    for {
+
+      fmt.Print("renderhive> ")
+      if !scanner.Scan() {
+        return nil
+      }
+
+      line := strings.TrimSpace(scanner.Text())
+      if line == "" {
+        continue
+      }
+      if line == "exit" || line == "quit" {
+        return nil
+      }
+
+      args := append([]string{"renderhive", "--socket", socketPath}, strings.Fields(line)...)
+      if err := consoleApp().Run(args); err != nil {
+        fmt.Fprintln(os.Stderr, err)
+      }
+
+    }
+
+  },
+}
+
+// consoleApp builds a fresh App for dispatching a single REPL line. It
+// cannot reuse NewApp: NewApp's Commands slice includes ConsoleCommand, so
+// calling NewApp from inside ConsoleCommand's own Action closed a genuine Go
+// initialization cycle (ConsoleCommand -> NewApp -> ConsoleCommand). This
+// mirrors NewApp's root Flags/Commands but omits ConsoleCommand itself, since
+// nesting the console inside itself makes no sense anyway.
+func consoleApp() *climod.App {
+
+  return &climod.App{
+    Name: "renderhive",
+    Flags: []climod.Flag{SocketFlag},
+    Commands: []*climod.Command{
+      NodeCommand,
+      TopicCommand,
+      JobCommand,
+      AccountCommand,
+    },
+  }
+
+}
@@ -0,0 +1,129 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file implements the `renderhive job` subcommand tree for submitting,
+listing, and cancelling render jobs on a running daemon.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+
+  // internal
+  "renderhive/admin"
+)
+
+// JobCommand is the `renderhive job` subcommand tree
+var JobCommand = &climod.Command{
+  Name: "job",
+  Usage: "Submit and manage render jobs",
+  Subcommands: []*climod.Command{
+    jobSubmitCommand,
+    jobListCommand,
+    jobCancelCommand,
+  },
+}
+
+var jobSubmitCommand = &climod.Command{
+  Name: "submit",
+  Usage: "Submit a render job description to the running daemon",
+  ArgsUsage: "<job-file>",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 1 {
+      return fmt.Errorf("expected exactly one argument: <job-file>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var jobID string
+    if err := client.Call(admin.RPCName+".SubmitJob", c.Args().First(), &jobID); err != nil {
+      return err
+    }
+
+    fmt.Printf("Submitted job %s\n", jobID)
+
+    return nil
+
+  },
+}
+
+var jobListCommand = &climod.Command{
+  Name: "list",
+  Usage: "List render jobs known to the running daemon",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var jobs []string
+    if err := client.Call(admin.RPCName+".ListJobs", struct{}{}, &jobs); err != nil {
+      return err
+    }
+
+    for _, job := range jobs {
+      fmt.Println(job)
+    }
+
+    return nil
+
+  },
+}
+
+var jobCancelCommand = &climod.Command{
+  Name: "cancel",
+  Usage: "Cancel a render job on the running daemon",
+  ArgsUsage: "<jobID>",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 1 {
+      return fmt.Errorf("expected exactly one argument: <jobID>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var ok bool
+    return client.Call(admin.RPCName+".CancelJob", c.Args().First(), &ok)
+
+  },
+}
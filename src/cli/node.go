@@ -0,0 +1,83 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file implements the `renderhive node` subcommand tree.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+
+  // internal
+  "renderhive/admin"
+)
+
+// NodeStatus is the result of the Admin.NodeStatus RPC call
+type NodeStatus struct {
+  AccountID string
+  Network string
+  MirrorNode string
+  HiveCycle uint64
+}
+
+// NodeCommand is the `renderhive node` subcommand tree
+var NodeCommand = &climod.Command{
+  Name: "node",
+  Usage: "Inspect and manage the local renderhive node",
+  Subcommands: []*climod.Command{
+    nodeStatusCommand,
+  },
+}
+
+var nodeStatusCommand = &climod.Command{
+  Name: "status",
+  Usage: "Show the status of the running renderhive daemon",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var status NodeStatus
+    if err := client.Call(admin.RPCName+".NodeStatus", struct{}{}, &status); err != nil {
+      return err
+    }
+
+    fmt.Printf("Account:     %s\n", status.AccountID)
+    fmt.Printf("Network:     %s\n", status.Network)
+    fmt.Printf("Mirror node: %s\n", status.MirrorNode)
+    fmt.Printf("Hive cycle:  %d\n", status.HiveCycle)
+
+    return nil
+
+  },
+}
@@ -0,0 +1,58 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file contains the minimal JSON-RPC client the `node`, `topic`, `job`,
+`account`, and `console` subcommands use to talk to an already running
+renderhive daemon over its admin Unix socket. The wire contract (method names
+and argument/result shapes) mirrors the `Admin.*` methods (admin.RPCName)
+that the daemon registers on that socket.
+
+*/
+
+import (
+
+  // standard
+  "net"
+  "net/rpc"
+  "net/rpc/jsonrpc"
+
+  // internal
+  "renderhive/app"
+)
+
+// DefaultSocketPath is where the daemon binds its admin JSON-RPC endpoint
+// unless overridden with --socket
+const DefaultSocketPath = app.DefaultAdminSocketPath
+
+// dialAdmin opens a JSON-RPC connection to the daemon's admin Unix socket
+func dialAdmin(socketPath string) (*rpc.Client, error) {
+
+  conn, err := net.Dial("unix", socketPath)
+  if err != nil {
+    return nil, err
+  }
+
+  return jsonrpc.NewClient(conn), nil
+
+}
@@ -0,0 +1,161 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package cli
+
+/*
+
+This file implements the `renderhive topic` subcommand tree, which lets an
+operator subscribe to, list, and send messages on Hedera Consensus Service
+topics of a running daemon.
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+  "time"
+
+  // external
+  climod "github.com/urfave/cli/v2"
+
+  // internal
+  "renderhive/admin"
+)
+
+// TopicCommand is the `renderhive topic` subcommand tree
+var TopicCommand = &climod.Command{
+  Name: "topic",
+  Usage: "Manage HCS topic subscriptions of the running daemon",
+  Subcommands: []*climod.Command{
+    topicSubscribeCommand,
+    topicListCommand,
+    topicSendCommand,
+    topicResetCheckpointCommand,
+  },
+}
+
+var topicSubscribeJobFlag = &climod.BoolFlag{
+  Name: "job",
+  Usage: "Track the topic as a render job topic instead of a plain HCS subscription",
+}
+
+var topicSubscribeCommand = &climod.Command{
+  Name: "subscribe",
+  Usage: "Subscribe the running daemon to an additional HCS topic",
+  ArgsUsage: "<topicID>",
+  Flags: []climod.Flag{SocketFlag, topicSubscribeJobFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 1 {
+      return fmt.Errorf("expected exactly one argument: <topicID>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    args := admin.AddTopicArgs{TopicID: c.Args().First(), StartTime: time.Unix(0, 0)}
+    method := admin.RPCName + ".AddTopic"
+    if c.Bool(topicSubscribeJobFlag.Name) {
+      method = admin.RPCName + ".AddJobTopic"
+    }
+
+    var topicID string
+    return client.Call(method, args, &topicID)
+
+  },
+}
+
+var topicListCommand = &climod.Command{
+  Name: "list",
+  Usage: "List the HCS topics the running daemon is subscribed to",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var topics []string
+    if err := client.Call(admin.RPCName + ".ListTopics", struct{}{}, &topics); err != nil {
+      return err
+    }
+
+    for _, topic := range topics {
+      fmt.Println(topic)
+    }
+
+    return nil
+
+  },
+}
+
+var topicSendCommand = &climod.Command{
+  Name: "send",
+  Usage: "Submit a message to an HCS topic via the running daemon",
+  ArgsUsage: "<topicID> <message>",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 2 {
+      return fmt.Errorf("expected exactly two arguments: <topicID> <message>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    args := struct{ TopicID, Message string }{c.Args().Get(0), c.Args().Get(1)}
+    var transactionID string
+    return client.Call(admin.RPCName+".SendTopicMessage", args, &transactionID)
+
+  },
+}
+
+var topicResetCheckpointCommand = &climod.Command{
+  Name: "reset-checkpoint",
+  Usage: "Reset the persisted checkpoint of a topic so it replays from the beginning",
+  ArgsUsage: "<topicID>",
+  Flags: []climod.Flag{SocketFlag},
+  Action: func(c *climod.Context) error {
+
+    if c.NArg() != 1 {
+      return fmt.Errorf("expected exactly one argument: <topicID>")
+    }
+
+    client, err := dialAdmin(c.String(SocketFlag.Name))
+    if err != nil {
+      return fmt.Errorf("could not reach the renderhive daemon: %w", err)
+    }
+    defer client.Close()
+
+    var ok bool
+    return client.Call(admin.RPCName + ".ResetTopicCheckpoint", c.Args().First(), &ok)
+
+  },
+}
@@ -0,0 +1,86 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package events
+
+/*
+
+This file defines the Bus interface renderhive publishes CloudEvents
+envelopes to, and MemoryBus, the in-process implementation backed by a plain
+Go channel. Other sinks (MQTT, NATS, Kafka) implement the same interface so
+ServiceApp can be wired to whichever one an operator configures.
+
+*/
+
+import (
+  "context"
+)
+
+// Bus is a pluggable sink for CloudEvents envelopes
+type Bus interface {
+
+  // Publish sends an envelope to the bus
+  Publish(ctx context.Context, event Envelope) error
+
+  // Close releases any resources held by the bus
+  Close() error
+
+}
+
+// MemoryBus is an in-process Bus backed by a buffered Go channel. It is the
+// default sink and is useful for tests and for embedding renderhive as a
+// library.
+type MemoryBus struct {
+  events chan Envelope
+}
+
+// NewMemoryBus creates a MemoryBus with the given channel buffer size
+func NewMemoryBus(buffer int) *MemoryBus {
+
+  return &MemoryBus{events: make(chan Envelope, buffer)}
+
+}
+
+// Publish enqueues an envelope, or returns ctx.Err() if the context is
+// cancelled before there is room on the channel
+func (bus *MemoryBus) Publish(ctx context.Context, event Envelope) error {
+
+  select {
+  case bus.events <- event:
+    return nil
+  case <-ctx.Done():
+    return ctx.Err()
+  }
+
+}
+
+// Events returns the channel new envelopes are delivered on
+func (bus *MemoryBus) Events() <-chan Envelope {
+  return bus.events
+}
+
+// Close closes the underlying channel
+func (bus *MemoryBus) Close() error {
+
+  close(bus.events)
+
+  return nil
+
+}
@@ -0,0 +1,91 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package events
+
+/*
+
+This file defines the CloudEvents v1.0 envelope every inbound HCS message is
+wrapped in before it reaches a Bus, and the event `type` values renderhive
+uses for its own topics.
+
+*/
+
+import (
+
+  // standard
+  "encoding/base64"
+  "encoding/json"
+  "fmt"
+  "time"
+
+)
+
+// Event type values renderhive attaches to the CloudEvents envelope,
+// following the reverse-DNS convention CloudEvents recommends
+const (
+  EventTypeHiveCycleSync = "io.renderhive.hivecycle.sync"
+  EventTypeHiveCycleApplication = "io.renderhive.hivecycle.application"
+  EventTypeHiveCycleValidation = "io.renderhive.hivecycle.validation"
+  EventTypeJobQueued = "io.renderhive.job.queued"
+  EventTypeTopicMessage = "io.renderhive.topic.message"
+)
+
+// Envelope is a CloudEvents v1.0 JSON envelope
+// (https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/formats/json-format.md)
+type Envelope struct {
+
+  SpecVersion string `json:"specversion"`
+  Type string `json:"type"`
+  Source string `json:"source"`
+  ID string `json:"id"`
+  Subject string `json:"subject,omitempty"`
+  Time time.Time `json:"time"`
+  DataContentType string `json:"datacontenttype,omitempty"`
+  Data json.RawMessage `json:"data,omitempty"`
+  DataBase64 string `json:"data_base64,omitempty"`
+
+}
+
+// NewEnvelope wraps a raw HCS message payload in a CloudEvents envelope. If
+// the payload is already valid JSON it is embedded as-is; otherwise it is
+// carried as base64 per the CloudEvents JSON binary-data convention.
+func NewEnvelope(topicID string, eventType string, consensusTimestamp time.Time, nodeAccount string, data []byte) Envelope {
+
+  envelope := Envelope{
+    SpecVersion: "1.0",
+    Type: eventType,
+    Source: fmt.Sprintf("hedera://%s", topicID),
+    ID: fmt.Sprintf("%d", consensusTimestamp.UnixNano()),
+    Subject: nodeAccount,
+    Time: consensusTimestamp,
+    DataContentType: "application/json",
+  }
+
+  if json.Valid(data) {
+    envelope.Data = json.RawMessage(data)
+  } else {
+    envelope.DataContentType = "application/octet-stream"
+    envelope.DataBase64 = base64.StdEncoding.EncodeToString(data)
+  }
+
+  return envelope
+
+}
@@ -0,0 +1,59 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package events
+
+import (
+  "testing"
+  "time"
+)
+
+func TestNewEnvelopeEmbedsValidJSON(t *testing.T) {
+
+  envelope := NewEnvelope("0.0.2002", EventTypeTopicMessage, time.Now(), "0.0.1001", []byte(`{"hello":"world"}`))
+
+  if envelope.DataContentType != "application/json" {
+    t.Fatalf("expected datacontenttype application/json, got %q", envelope.DataContentType)
+  }
+  if string(envelope.Data) != `{"hello":"world"}` {
+    t.Fatalf("expected Data to carry the raw JSON payload, got %q", string(envelope.Data))
+  }
+  if envelope.DataBase64 != "" {
+    t.Fatalf("expected DataBase64 to be empty for a JSON payload, got %q", envelope.DataBase64)
+  }
+
+}
+
+func TestNewEnvelopeBase64EncodesNonJSON(t *testing.T) {
+
+  payload := []byte{0x00, 0x01, 0x02, 0xff}
+  envelope := NewEnvelope("0.0.2002", EventTypeTopicMessage, time.Now(), "0.0.1001", payload)
+
+  if envelope.DataContentType != "application/octet-stream" {
+    t.Fatalf("expected datacontenttype application/octet-stream, got %q", envelope.DataContentType)
+  }
+  if envelope.Data != nil {
+    t.Fatalf("expected Data to be empty for a non-JSON payload, got %q", string(envelope.Data))
+  }
+  if envelope.DataBase64 != "AAEC/w==" {
+    t.Fatalf("expected base64-encoded payload, got %q", envelope.DataBase64)
+  }
+
+}
@@ -0,0 +1,87 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package events
+
+/*
+
+This file contains the ingress side of the event bus: an HTTP handler that
+accepts a CloudEvents v1.0 JSON-encoded event (e.g. a job-submission event
+from an external scheduler) and forwards its payload to the renderhive HCS
+topic named in the "renderhivetopicid" CloudEvents extension attribute.
+
+*/
+
+import (
+
+  // standard
+  "encoding/json"
+  "fmt"
+  "net/http"
+
+)
+
+// Forwarder submits a raw payload to an HCS topic. ServiceApp implements this
+// on top of its HederaManager.
+type Forwarder interface {
+  SubmitTopicMessage(topicID string, payload []byte) error
+}
+
+// ingressEvent is the subset of a CloudEvents v1.0 JSON event the ingress
+// handler needs; unknown fields (other CloudEvents attributes) are ignored
+type ingressEvent struct {
+  Type string `json:"type"`
+  Source string `json:"source"`
+  TopicID string `json:"renderhivetopicid"`
+  Data json.RawMessage `json:"data"`
+}
+
+// IngressHandler returns an http.Handler that accepts a CloudEvents JSON body
+// on POST and forwards its data to the HCS topic it names
+func IngressHandler(forwarder Forwarder) http.Handler {
+
+  return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+    if r.Method != http.MethodPost {
+      http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+      return
+    }
+
+    var event ingressEvent
+    if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+      http.Error(w, fmt.Sprintf("invalid CloudEvents payload: %v", err), http.StatusBadRequest)
+      return
+    }
+
+    if event.TopicID == "" {
+      http.Error(w, "missing \"renderhivetopicid\" extension attribute", http.StatusBadRequest)
+      return
+    }
+
+    if err := forwarder.SubmitTopicMessage(event.TopicID, event.Data); err != nil {
+      http.Error(w, fmt.Sprintf("could not forward event to HCS: %v", err), http.StatusBadGateway)
+      return
+    }
+
+    w.WriteHeader(http.StatusAccepted)
+
+  })
+
+}
@@ -0,0 +1,122 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package events
+
+/*
+
+This file contains MQTTBus, a Bus implementation that publishes CloudEvents
+envelopes to an MQTT broker. It is written against the same paho MQTT client
+the mochi-mqtt-embedded setups (like the one used in the ocm project) expose
+to publishers, so it works unchanged whether the broker is an embedded
+mochi-mqtt instance or an external one such as Mosquitto or EMQX.
+
+*/
+
+import (
+
+  // standard
+  "context"
+  "encoding/json"
+  "fmt"
+  "strings"
+  "time"
+
+  // external
+  mqtt "github.com/eclipse/paho.mqtt.golang"
+
+)
+
+// MQTTBus publishes CloudEvents envelopes to an MQTT broker
+type MQTTBus struct {
+
+  client mqtt.Client
+
+  // TopicPrefix is prepended to the renderhive topic ID to form the MQTT
+  // topic an envelope is published on, e.g. "renderhive/events/0.0.1234567"
+  TopicPrefix string
+
+  // QoS is the MQTT quality of service level used for publishes
+  QoS byte
+
+}
+
+// NewMQTTBus connects to the broker at brokerURL (e.g. "tcp://localhost:1883")
+// and returns a Bus that publishes to it
+func NewMQTTBus(brokerURL string, clientID string, topicPrefix string) (*MQTTBus, error) {
+
+  options := mqtt.NewClientOptions().
+    AddBroker(brokerURL).
+    SetClientID(clientID).
+    SetConnectTimeout(10 * time.Second)
+
+  client := mqtt.NewClient(options)
+  if token := client.Connect(); token.Wait() && token.Error() != nil {
+    return nil, token.Error()
+  }
+
+  return &MQTTBus{client: client, TopicPrefix: topicPrefix, QoS: 1}, nil
+
+}
+
+// Publish marshals the envelope to JSON and publishes it on
+// "<TopicPrefix>/<source-topic-id>"
+func (bus *MQTTBus) Publish(ctx context.Context, event Envelope) error {
+
+  payload, err := json.Marshal(event)
+  if err != nil {
+    return err
+  }
+
+  topicID := strings.TrimPrefix(event.Source, "hedera://")
+  mqttTopic := fmt.Sprintf("%s/%s", bus.TopicPrefix, topicID)
+  token := bus.client.Publish(mqttTopic, bus.QoS, false, payload)
+
+  select {
+  case <-tokenDone(token):
+    return token.Error()
+  case <-ctx.Done():
+    return ctx.Err()
+  }
+
+}
+
+// Close disconnects from the broker
+func (bus *MQTTBus) Close() error {
+
+  bus.client.Disconnect(250)
+
+  return nil
+
+}
+
+// tokenDone adapts a paho Token's Wait() into a channel so Publish can select
+// on it alongside the caller's context
+func tokenDone(token mqtt.Token) <-chan struct{} {
+
+  done := make(chan struct{})
+  go func() {
+    token.Wait()
+    close(done)
+  }()
+
+  return done
+
+}
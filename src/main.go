@@ -0,0 +1,51 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package main
+
+/*
+
+This is the entry point of the renderhive binary. It merely hands off to the
+renderhive/cli package, which boots the ServiceApp and dispatches to the
+requested subcommand (in the style of the geth binary's cmd/geth/main.go).
+
+*/
+
+import (
+
+  // standard
+  "fmt"
+  "os"
+
+  // internal
+  "renderhive/cli"
+)
+
+// FUNCTIONS
+// #############################################################################
+// Entry point of the renderhive service app binary
+func main() {
+
+  if err := cli.App.Run(os.Args); err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+
+}
@@ -0,0 +1,65 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package scheduler
+
+/*
+
+This file declares the Prometheus metrics the scheduler and its tasks report.
+They are registered with the default registry on package init, so the web app
+manager only has to expose promhttp.Handler() on /metrics.
+
+*/
+
+import (
+
+  // external
+  "github.com/prometheus/client_golang/prometheus"
+
+)
+
+var (
+
+  // TaskRunsTotal counts every completed run of a PeriodicTask, partitioned
+  // by task name and result ("success" or "error")
+  TaskRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+    Name: "renderhive_task_runs_total",
+    Help: "Total number of scheduled task runs, partitioned by task and result.",
+  }, []string{"task", "result"})
+
+  // TaskDurationSeconds observes how long a single PeriodicTask run took
+  TaskDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+    Name: "renderhive_task_duration_seconds",
+    Help: "Duration of a single scheduled task run, in seconds.",
+  }, []string{"task"})
+
+  // HiveCycleCurrent is the Unix timestamp of the most recently completed
+  // hive cycle synchronization; /readyz derives staleness from the same
+  // underlying value, this just makes it scrapeable
+  HiveCycleCurrent = prometheus.NewGauge(prometheus.GaugeOpts{
+    Name: "renderhive_hive_cycle_current",
+    Help: "Unix timestamp of the most recently completed hive cycle synchronization.",
+  })
+
+)
+
+func init() {
+  prometheus.MustRegister(TaskRunsTotal, TaskDurationSeconds, HiveCycleCurrent)
+}
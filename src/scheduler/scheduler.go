@@ -0,0 +1,227 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package scheduler
+
+/*
+
+This package extracts the inline hive-cycle goroutine that used to live in
+ServiceApp.Init into a reusable PeriodicTask runner. Every registered task
+shares the same context and wait group, so a single cancellation and
+WG.Wait() deterministically stops all of them. A task that returns an error
+is retried with exponential backoff and jitter instead of busy-looping or
+taking the whole daemon down; a task that panics is recovered, reported to
+Sentry, and then logged and backed off the same way a returned error is.
+
+*/
+
+import (
+
+  // standard
+  "context"
+  "fmt"
+  "math/rand"
+  "sync"
+  "time"
+
+  // external
+  "github.com/getsentry/sentry-go"
+
+  // internal
+  "renderhive/logger"
+)
+
+// MinBackoff and MaxBackoff bound the exponential-backoff-with-jitter delay
+// applied after a task returns an error or panics
+const (
+  MinBackoff = 1 * time.Second
+  MaxBackoff = 5 * time.Minute
+)
+
+// PeriodicTask is a unit of recurring work the Scheduler runs until the
+// Scheduler's context is cancelled
+type PeriodicTask struct {
+
+  // name the task is identified by in logs, metrics, and Sentry tags
+  Name string
+
+  // NextRun returns how long to wait before the next run after a
+  // successful one; it is called fresh every time so a task can derive its
+  // own cadence from state that may change at runtime (e.g.
+  // HiveCycle.Configurations)
+  NextRun func() time.Duration
+
+  // Run performs one iteration of the task; a returned error (or a panic,
+  // which is recovered and turned into an error) triggers backoff instead
+  // of NextRun
+  Run func(ctx context.Context) error
+
+}
+
+// Scheduler runs a set of PeriodicTasks, each on its own goroutine, sharing
+// a single context and wait group so they can all be stopped deterministically
+type Scheduler struct {
+
+  ctx context.Context
+  wg *sync.WaitGroup
+
+}
+
+// New creates a Scheduler whose tasks stop as soon as ctx is cancelled; wg is
+// the same wait group the caller waits on during shutdown
+func New(ctx context.Context, wg *sync.WaitGroup) *Scheduler {
+
+  return &Scheduler{ctx: ctx, wg: wg}
+
+}
+
+// Register starts a PeriodicTask in the background; it keeps running until
+// the Scheduler's context is cancelled
+func (scheduler *Scheduler) Register(task PeriodicTask) {
+
+  scheduler.wg.Add(1)
+  go scheduler.run(task)
+
+}
+
+// run is the per-task loop: execute, then wait either for the next run or
+// for shutdown, whichever comes first
+func (scheduler *Scheduler) run(task PeriodicTask) {
+
+  defer scheduler.wg.Done()
+
+  attempt := 0
+
+  for {
+
+    wait := scheduler.execute(task, &attempt)
+
+    select {
+
+    case <-scheduler.ctx.Done():
+      logger.RenderhiveLogger.Main.Debug().Msg(fmt.Sprintf("Stopped scheduled task %q.", task.Name))
+      return
+
+    case <-time.After(wait):
+
+    }
+
+  }
+
+}
+
+// execute runs one iteration of task, records its metrics, and returns how
+// long to wait until the next one
+func (scheduler *Scheduler) execute(task PeriodicTask, attempt *int) time.Duration {
+
+  start := time.Now()
+  err := scheduler.invoke(task)
+
+  TaskDurationSeconds.WithLabelValues(task.Name).Observe(time.Since(start).Seconds())
+
+  if err != nil {
+    TaskRunsTotal.WithLabelValues(task.Name, "error").Inc()
+    logger.RenderhiveLogger.Main.Warn().Msg(fmt.Sprintf("Scheduled task %q failed: %v", task.Name, err))
+
+    wait := backoffWithJitter(*attempt)
+    *attempt++
+
+    return wait
+  }
+
+  TaskRunsTotal.WithLabelValues(task.Name, "success").Inc()
+
+  // NextRun is caller-supplied too (e.g. it may index into live
+  // configuration), so it gets the same panic recovery and backoff as Run
+  // instead of being able to take the scheduler down
+  wait, err := scheduler.nextRun(task)
+  if err != nil {
+    TaskRunsTotal.WithLabelValues(task.Name, "error").Inc()
+    logger.RenderhiveLogger.Main.Warn().Msg(fmt.Sprintf("Scheduled task %q: NextRun failed: %v", task.Name, err))
+
+    wait = backoffWithJitter(*attempt)
+    *attempt++
+
+    return wait
+  }
+
+  *attempt = 0
+
+  return wait
+
+}
+
+// invoke runs a single task iteration, recovering from and reporting panics
+// the same way a returned error is reported, so one bad task cannot take the
+// whole scheduler down
+func (scheduler *Scheduler) invoke(task PeriodicTask) (err error) {
+
+  defer func() {
+    if r := recover(); r != nil {
+      sentry.WithScope(func(scope *sentry.Scope) {
+        scope.SetTag("task", task.Name)
+        sentry.CaptureException(fmt.Errorf("panic in scheduled task %q: %v", task.Name, r))
+      })
+      err = fmt.Errorf("panic in task %q: %v", task.Name, r)
+    }
+  }()
+
+  return task.Run(scheduler.ctx)
+
+}
+
+// nextRun calls task.NextRun(), recovering from and reporting panics the same
+// way invoke does for task.Run
+func (scheduler *Scheduler) nextRun(task PeriodicTask) (wait time.Duration, err error) {
+
+  defer func() {
+    if r := recover(); r != nil {
+      sentry.WithScope(func(scope *sentry.Scope) {
+        scope.SetTag("task", task.Name)
+        sentry.CaptureException(fmt.Errorf("panic in NextRun of scheduled task %q: %v", task.Name, r))
+      })
+      err = fmt.Errorf("panic in NextRun of task %q: %v", task.Name, r)
+    }
+  }()
+
+  return task.NextRun(), nil
+
+}
+
+// backoffWithJitter returns a delay that grows exponentially with attempt,
+// capped at MaxBackoff, with up to 50% jitter to avoid retry storms across
+// tasks that fail at the same time
+func backoffWithJitter(attempt int) time.Duration {
+
+  // cap attempt before shifting so the duration cannot overflow
+  if attempt > 10 {
+    attempt = 10
+  }
+
+  backoff := MinBackoff * time.Duration(int64(1)<<uint(attempt))
+  if backoff > MaxBackoff {
+    backoff = MaxBackoff
+  }
+
+  jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+
+  return backoff/2 + jitter
+
+}
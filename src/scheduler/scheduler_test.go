@@ -0,0 +1,130 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package scheduler
+
+import (
+  "context"
+  "sync"
+  "testing"
+  "time"
+)
+
+func TestBackoffWithJitterGrowsAndCaps(t *testing.T) {
+
+  for attempt := 0; attempt <= 4; attempt++ {
+
+    backoff := MinBackoff * time.Duration(int64(1)<<uint(attempt))
+
+    for i := 0; i < 20; i++ {
+      wait := backoffWithJitter(attempt)
+      if wait < backoff/2 || wait > backoff {
+        t.Fatalf("attempt %d: wait %v out of expected range [%v, %v]", attempt, wait, backoff/2, backoff)
+      }
+    }
+
+  }
+
+  for i := 0; i < 20; i++ {
+    wait := backoffWithJitter(30)
+    if wait > MaxBackoff {
+      t.Fatalf("wait %v exceeds MaxBackoff %v for a large attempt count", wait, MaxBackoff)
+    }
+  }
+
+}
+
+func TestExecuteRecoversPanicInRun(t *testing.T) {
+
+  scheduler := New(context.Background(), &sync.WaitGroup{})
+
+  task := PeriodicTask{
+    Name: "panicking-run",
+    Run: func(ctx context.Context) error {
+      panic("boom")
+    },
+    NextRun: func() time.Duration {
+      return time.Minute
+    },
+  }
+
+  attempt := 0
+  wait := scheduler.execute(task, &attempt)
+
+  if attempt != 1 {
+    t.Fatalf("expected attempt to advance to 1 after a panic in Run, got %d", attempt)
+  }
+  if wait <= 0 || wait > MaxBackoff {
+    t.Fatalf("expected a backoff wait after a panic in Run, got %v", wait)
+  }
+
+}
+
+func TestExecuteRecoversPanicInNextRun(t *testing.T) {
+
+  scheduler := New(context.Background(), &sync.WaitGroup{})
+
+  task := PeriodicTask{
+    Name: "panicking-nextrun",
+    Run: func(ctx context.Context) error {
+      return nil
+    },
+    NextRun: func() time.Duration {
+      panic("boom")
+    },
+  }
+
+  attempt := 0
+  wait := scheduler.execute(task, &attempt)
+
+  if attempt != 1 {
+    t.Fatalf("expected attempt to advance to 1 after a panic in NextRun, got %d", attempt)
+  }
+  if wait <= 0 || wait > MaxBackoff {
+    t.Fatalf("expected a backoff wait after a panic in NextRun, got %v", wait)
+  }
+
+}
+
+func TestExecuteResetsAttemptOnSuccess(t *testing.T) {
+
+  scheduler := New(context.Background(), &sync.WaitGroup{})
+
+  task := PeriodicTask{
+    Name: "healthy",
+    Run: func(ctx context.Context) error {
+      return nil
+    },
+    NextRun: func() time.Duration {
+      return 42 * time.Second
+    },
+  }
+
+  attempt := 3
+  wait := scheduler.execute(task, &attempt)
+
+  if attempt != 0 {
+    t.Fatalf("expected attempt to reset to 0 after a successful run, got %d", attempt)
+  }
+  if wait != 42*time.Second {
+    t.Fatalf("expected execute to pass NextRun's duration through, got %v", wait)
+  }
+
+}
@@ -0,0 +1,119 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package store
+
+/*
+
+This file contains BoltCheckpointStore, the embedded-BoltDB backed
+implementation of CheckpointStore used by the daemon by default.
+
+*/
+
+import (
+
+  // standard
+  "time"
+
+  // external
+  bolt "go.etcd.io/bbolt"
+
+)
+
+// checkpointsBucket is the single bucket all checkpoints are stored in
+var checkpointsBucket = []byte("checkpoints")
+
+// BoltCheckpointStore persists checkpoints in a local BoltDB file
+type BoltCheckpointStore struct {
+  db *bolt.DB
+}
+
+// NewBoltCheckpointStore opens (and creates, if necessary) a BoltDB file at
+// path and prepares the checkpoints bucket
+func NewBoltCheckpointStore(path string) (*BoltCheckpointStore, error) {
+
+  db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+  if err != nil {
+    return nil, err
+  }
+
+  err = db.Update(func(tx *bolt.Tx) error {
+    _, err := tx.CreateBucketIfNotExists(checkpointsBucket)
+    return err
+  })
+  if err != nil {
+    db.Close()
+    return nil, err
+  }
+
+  return &BoltCheckpointStore{db: db}, nil
+
+}
+
+// Get returns the last checkpointed timestamp for the topic
+func (store *BoltCheckpointStore) Get(nodeAccount string, topicID string) (time.Time, bool, error) {
+
+  var timestamp time.Time
+  var found bool
+
+  err := store.db.View(func(tx *bolt.Tx) error {
+
+    value := tx.Bucket(checkpointsBucket).Get([]byte(key(nodeAccount, topicID)))
+    if value == nil {
+      return nil
+    }
+
+    found = true
+
+    return timestamp.UnmarshalBinary(value)
+
+  })
+
+  return timestamp, found, err
+
+}
+
+// Set records the last successfully processed consensus timestamp
+func (store *BoltCheckpointStore) Set(nodeAccount string, topicID string, timestamp time.Time) error {
+
+  value, err := timestamp.MarshalBinary()
+  if err != nil {
+    return err
+  }
+
+  return store.db.Update(func(tx *bolt.Tx) error {
+    return tx.Bucket(checkpointsBucket).Put([]byte(key(nodeAccount, topicID)), value)
+  })
+
+}
+
+// Reset removes the checkpoint for a topic
+func (store *BoltCheckpointStore) Reset(nodeAccount string, topicID string) error {
+
+  return store.db.Update(func(tx *bolt.Tx) error {
+    return tx.Bucket(checkpointsBucket).Delete([]byte(key(nodeAccount, topicID)))
+  })
+
+}
+
+// Close closes the underlying BoltDB file
+func (store *BoltCheckpointStore) Close() error {
+  return store.db.Close()
+}
@@ -0,0 +1,108 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package store
+
+import (
+  "path/filepath"
+  "testing"
+  "time"
+)
+
+func openTestStore(t *testing.T) *BoltCheckpointStore {
+
+  t.Helper()
+
+  store, err := NewBoltCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.db"))
+  if err != nil {
+    t.Fatalf("NewBoltCheckpointStore failed: %v", err)
+  }
+  t.Cleanup(func() { store.Close() })
+
+  return store
+
+}
+
+func TestBoltCheckpointStoreGetMissing(t *testing.T) {
+
+  store := openTestStore(t)
+
+  _, found, err := store.Get("0.0.1001", "0.0.2002")
+  if err != nil {
+    t.Fatalf("Get failed: %v", err)
+  }
+  if found {
+    t.Fatalf("expected found to be false for a checkpoint that was never set")
+  }
+
+}
+
+func TestBoltCheckpointStoreSetThenGet(t *testing.T) {
+
+  store := openTestStore(t)
+
+  want := time.Now().UTC().Truncate(time.Nanosecond)
+  if err := store.Set("0.0.1001", "0.0.2002", want); err != nil {
+    t.Fatalf("Set failed: %v", err)
+  }
+
+  got, found, err := store.Get("0.0.1001", "0.0.2002")
+  if err != nil {
+    t.Fatalf("Get failed: %v", err)
+  }
+  if !found {
+    t.Fatalf("expected found to be true after Set")
+  }
+  if !got.Equal(want) {
+    t.Fatalf("expected checkpoint %v, got %v", want, got)
+  }
+
+  // a different topic on the same node must not see this checkpoint
+  _, found, err = store.Get("0.0.1001", "0.0.3003")
+  if err != nil {
+    t.Fatalf("Get failed: %v", err)
+  }
+  if found {
+    t.Fatalf("expected found to be false for an unrelated topic")
+  }
+
+}
+
+func TestBoltCheckpointStoreReset(t *testing.T) {
+
+  store := openTestStore(t)
+
+  if err := store.Set("0.0.1001", "0.0.2002", time.Now()); err != nil {
+    t.Fatalf("Set failed: %v", err)
+  }
+
+  if err := store.Reset("0.0.1001", "0.0.2002"); err != nil {
+    t.Fatalf("Reset failed: %v", err)
+  }
+
+  _, found, err := store.Get("0.0.1001", "0.0.2002")
+  if err != nil {
+    t.Fatalf("Get failed: %v", err)
+  }
+  if found {
+    t.Fatalf("expected found to be false after Reset")
+  }
+
+}
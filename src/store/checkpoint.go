@@ -0,0 +1,62 @@
+/*
+ * ************************** BEGIN LICENSE BLOCK ******************************
+ *
+ * Copyright © 2023 Christian Stolze
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * ************************** END LICENSE BLOCK ********************************
+ */
+
+package store
+
+/*
+
+This file defines CheckpointStore, the interface ServiceApp uses around its
+HCS topic subscriptions to persist the last consensus timestamp processed for a
+given
+(nodeAccount, topicID) pair, so a restart resumes a topic instead of replaying
+it from the beginning. HederaManager itself is not given the store; ServiceApp
+passes it only the resolved start time.
+
+*/
+
+import (
+  "time"
+)
+
+// CheckpointStore persists the last successfully processed consensus
+// timestamp per (nodeAccount, topicID)
+type CheckpointStore interface {
+
+  // Get returns the last checkpointed timestamp for the topic, and false if
+  // no checkpoint has been recorded yet
+  Get(nodeAccount string, topicID string) (time.Time, bool, error)
+
+  // Set records the last successfully processed consensus timestamp
+  Set(nodeAccount string, topicID string, timestamp time.Time) error
+
+  // Reset removes the checkpoint for a topic, so the next subscription
+  // replays it from the beginning (e.g. for reindexing)
+  Reset(nodeAccount string, topicID string) error
+
+  // Close releases the underlying storage
+  Close() error
+
+}
+
+// key builds the composite (nodeAccount, topicID) key checkpoints are stored
+// under
+func key(nodeAccount string, topicID string) string {
+  return nodeAccount + "/" + topicID
+}